@@ -0,0 +1,196 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+
+	"github.com/fentec-project/gofe/innerprod/simple"
+
+	"github.com/vcb/go-fe-demo/keyio"
+)
+
+// runSubcommand dispatches one of the file-based DDH subcommands (setup,
+// encrypt, derive-key, decrypt). It reports whether name was a recognized
+// subcommand; the caller falls back to the interactive menu otherwise.
+func runSubcommand(name string, args []string) bool {
+	switch name {
+	case "setup":
+		cmdSetup(args)
+	case "encrypt":
+		cmdEncrypt(args)
+	case "derive-key":
+		cmdDeriveKey(args)
+	case "decrypt":
+		cmdDecrypt(args)
+	default:
+		return false
+	}
+	return true
+}
+
+func cmdSetup(args []string) {
+	fs := flag.NewFlagSet("setup", flag.ExitOnError)
+	l := fs.Int("l", 2, "vector length")
+	paramsOut := fs.String("params-out", "params.json", "path to write the DDH parameters")
+	mskOut := fs.String("msk-out", "msk.json", "path to write the master secret key")
+	mpkOut := fs.String("mpk-out", "mpk.json", "path to write the master public key")
+	fs.Parse(args)
+
+	ddh, err := simple.NewDDH(*l, ModLen, new(big.Int).SetUint64(Bound))
+	if err != nil {
+		fatalf("failed to instantiate DDH: %s", err)
+	}
+	msk, mpk, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		fatalf("failed to generate keys: %s", err)
+	}
+
+	fp := keyio.ParamsFingerprint(ddh.Params)
+	raw, err := keyio.EncodeParamsJSON(ddh.Params)
+	writeJSONArtifact(*paramsOut, raw, err)
+	raw, err = keyio.EncodeVectorJSON(keyio.KindDDHSecKey, fp, msk)
+	writeJSONArtifact(*mskOut, raw, err)
+	raw, err = keyio.EncodeVectorJSON(keyio.KindDDHPubKey, fp, mpk)
+	writeJSONArtifact(*mpkOut, raw, err)
+	fmt.Printf("Wrote %s, %s and %s\n", *paramsOut, *mskOut, *mpkOut)
+}
+
+func cmdEncrypt(args []string) {
+	fs := flag.NewFlagSet("encrypt", flag.ExitOnError)
+	paramsIn := fs.String("params-in", "params.json", "path to read the DDH parameters from")
+	mpkIn := fs.String("mpk-in", "mpk.json", "path to read the master public key from")
+	x := fs.String("x", "", "plaintext vector <x> as comma-separated integers")
+	cipherOut := fs.String("cipher-out", "cipher.json", "path to write the ciphertext")
+	fs.Parse(args)
+
+	ddh := loadDDH(*paramsIn)
+	fp := keyio.ParamsFingerprint(ddh.Params)
+
+	mpk, err := keyio.DecodeVectorJSON(readFile(*mpkIn), keyio.KindDDHPubKey, fp)
+	if err != nil {
+		fatalf("failed to read %s: %s", *mpkIn, err)
+	}
+
+	vecX, err := VecFromStr(*x)
+	if err != nil {
+		fatalf("failed to parse -x: %s", err)
+	}
+
+	c, err := ddh.Encrypt(vecX, mpk)
+	if err != nil {
+		fatalf("failed to encrypt: %s", err)
+	}
+	raw, err := keyio.EncodeVectorJSON(keyio.KindCipherVector, fp, c)
+	writeJSONArtifact(*cipherOut, raw, err)
+	fmt.Printf("Wrote %s\n", *cipherOut)
+}
+
+func cmdDeriveKey(args []string) {
+	fs := flag.NewFlagSet("derive-key", flag.ExitOnError)
+	paramsIn := fs.String("params-in", "params.json", "path to read the DDH parameters from")
+	mskIn := fs.String("msk-in", "msk.json", "path to read the master secret key from")
+	y := fs.String("y", "", "query vector <y> as comma-separated integers")
+	keyOut := fs.String("key-out", "feKey.json", "path to write the functional decryption key")
+	fs.Parse(args)
+
+	ddh := loadDDH(*paramsIn)
+	fp := keyio.ParamsFingerprint(ddh.Params)
+
+	msk, err := keyio.DecodeVectorJSON(readFile(*mskIn), keyio.KindDDHSecKey, fp)
+	if err != nil {
+		fatalf("failed to read %s: %s", *mskIn, err)
+	}
+
+	vecY, err := VecFromStr(*y)
+	if err != nil {
+		fatalf("failed to parse -y: %s", err)
+	}
+
+	feKey, err := ddh.DeriveKey(msk, vecY)
+	if err != nil {
+		fatalf("failed to derive key: %s", err)
+	}
+	raw, err := keyio.EncodeScalarJSON(keyio.KindFEKey, fp, feKey)
+	writeJSONArtifact(*keyOut, raw, err)
+	fmt.Printf("Wrote %s\n", *keyOut)
+}
+
+func cmdDecrypt(args []string) {
+	fs := flag.NewFlagSet("decrypt", flag.ExitOnError)
+	paramsIn := fs.String("params-in", "params.json", "path to read the DDH parameters from")
+	cipherIn := fs.String("cipher-in", "cipher.json", "path to read the ciphertext from")
+	keyIn := fs.String("key-in", "feKey.json", "path to read the functional decryption key from")
+	y := fs.String("y", "", "query vector <y> as comma-separated integers")
+	fs.Parse(args)
+
+	ddh := loadDDH(*paramsIn)
+	fp := keyio.ParamsFingerprint(ddh.Params)
+
+	c, err := keyio.DecodeVectorJSON(readFile(*cipherIn), keyio.KindCipherVector, fp)
+	if err != nil {
+		fatalf("failed to read %s: %s", *cipherIn, err)
+	}
+	feKey, err := keyio.DecodeScalarJSON(readFile(*keyIn), keyio.KindFEKey, fp)
+	if err != nil {
+		fatalf("failed to read %s: %s", *keyIn, err)
+	}
+
+	vecY, err := VecFromStr(*y)
+	if err != nil {
+		fatalf("failed to parse -y: %s", err)
+	}
+
+	fDec, err := ddh.Decrypt(c, feKey, vecY)
+	if err != nil {
+		fatalf("failed to decrypt: %s", err)
+	}
+	fmt.Printf("Decrypted inner product: %s\n", fDec)
+}
+
+// loadDDH rebuilds a DDH instance from a DDHParams artifact written by
+// setup, rather than generating a fresh (and incompatible) random group.
+func loadDDH(paramsPath string) *simple.DDH {
+	p, err := keyio.DecodeParamsJSON(readFile(paramsPath))
+	if err != nil {
+		fatalf("failed to read %s: %s", paramsPath, err)
+	}
+	return &simple.DDH{Params: p}
+}
+
+func readFile(path string) []byte {
+	if path == "-" {
+		raw, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fatalf("failed to read stdin: %s", err)
+		}
+		return raw
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		fatalf("failed to read %s: %s", path, err)
+	}
+	return raw
+}
+
+func writeJSONArtifact(path string, raw []byte, err error) {
+	if err != nil {
+		fatalf("failed to encode artifact: %s", err)
+	}
+	if path == "-" {
+		if _, err := os.Stdout.Write(raw); err != nil {
+			fatalf("failed to write stdout: %s", err)
+		}
+		return
+	}
+	if err := os.WriteFile(path, raw, 0o644); err != nil {
+		fatalf("failed to write %s: %s", path, err)
+	}
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
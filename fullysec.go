@@ -0,0 +1,309 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/fullysec"
+)
+
+func cliDamgard() {
+	for {
+		vecX, err := readVector("Enter the first vector <x> as comma-separated integers (e.g. <5,128,1,48,3>): ")
+		if err != nil {
+			fmt.Printf("Error reading input, please try again (%s)\n", err)
+			continue
+		}
+		if len(vecX) < 2 {
+			fmt.Println("Vector should have at least 2 elements.")
+			continue
+		}
+
+		vecY, err := readVector("Enter the second vector <y>: ")
+		if err != nil {
+			fmt.Printf("Error reading input, please try again (%s)\n", err)
+			continue
+		}
+		if len(vecX) != len(vecY) {
+			fmt.Printf("Vectors should be of the same length, please try again.\n")
+			continue
+		}
+
+		damgard, err := fullysec.NewDamgard(len(vecX), ModLen, new(big.Int).SetUint64(Bound))
+		if err != nil {
+			fmt.Printf("Failed to instantiate Damgard: %s\n", err)
+			continue
+		}
+
+		msk, mpk, err := damgard.GenerateMasterKeys()
+		if err != nil {
+			fmt.Printf("Failed to generate keys: %s\n", err)
+			continue
+		}
+
+		fmt.Printf("-----------------\n")
+		p := damgard.Params
+		PrintParams("Damgard", field("L", p.L), field("Bound", p.Bound))
+		fmt.Printf("-----------------\n")
+
+		// Encrypt the first vector
+		c, err := damgard.Encrypt(vecX, mpk)
+		if err != nil {
+			fmt.Printf("Failed to encrypt vector: %s\n", err)
+			continue
+		}
+		fmt.Printf("Encrypted vector: [%s ]\n", c)
+
+		// Derive the functional encryption key
+		feKey, err := damgard.DeriveKey(msk, vecY)
+		if err != nil {
+			fmt.Printf("Failed to derive functional encryption key: %s\n", err)
+			continue
+		}
+		fmt.Printf("Functional encryption key: %s\n", feKey)
+
+		// Decrypt the encrypted vector with the functional key
+		fDec, err := damgard.Decrypt(c, feKey, vecY)
+		if err != nil {
+			fmt.Printf("Failed to decrypt: %s\n", err)
+			continue
+		}
+		fmt.Printf("Decrypted inner product: %s\n", fDec)
+
+		// Calculate the inner product to verify the decryption
+		innerProd := big.NewInt(0)
+		for i := range vecX {
+			innerProd.Add(innerProd, new(big.Int).Mul(vecX[i], vecY[i]))
+		}
+
+		if innerProd.Cmp(fDec) == 0 {
+			fmt.Printf("Inner product check 🆗: %s\n", innerProd)
+		} else {
+			fmt.Printf("Inner product check failed: %d != %d\n", innerProd, fDec)
+		}
+		break
+	}
+}
+
+func cliDamgardMulti() {
+	for {
+		fmt.Printf("Enter the number of encryptors: ")
+		var numClients int
+		n, err := fmt.Scanln(&numClients)
+		if err != nil || n == 0 {
+			fmt.Printf("Error reading input, please try again (%s)\n", err)
+			continue
+		}
+
+		fmt.Printf("Enter the length of vectors: ")
+		var vecLen int
+		n, err = fmt.Scanln(&vecLen)
+		if err != nil || n == 0 {
+			fmt.Printf("Error reading input, please try again (%s)\n", err)
+			continue
+		}
+
+		damgard, err := fullysec.NewDamgardMulti(numClients, vecLen, ModLen, new(big.Int).SetUint64(Bound))
+		if err != nil {
+			fmt.Printf("Failed to instantiate DamgardMulti: %s\n", err)
+			continue
+		}
+
+		mpk, msk, err := damgard.GenerateMasterKeys()
+		if err != nil {
+			fmt.Printf("Failed to generate keys: %s\n", err)
+			continue
+		}
+
+		fmt.Printf("-----------------\n")
+		p := damgard.Params
+		PrintParams("DamgardMulti", field("L", p.L), field("Bound", p.Bound))
+		fmt.Printf("-----------------\n")
+
+		clients := make([]*fullysec.DamgardMultiClient, numClients)
+		for i := range clients {
+			clients[i] = fullysec.NewDamgardMultiClient(damgard.Params)
+		}
+
+		var xVecs []data.Vector
+		ciphers := make([]data.Vector, numClients)
+		for i := range clients {
+			fmt.Printf("(%d/%d) Enter the vector <x> as comma-separated integers: ", i+1, numClients)
+
+			var xInput string
+			n, err = fmt.Scanln(&xInput)
+			if err != nil || n == 0 {
+				fmt.Printf("Error reading input, please try again (%s)\n", err)
+				continue
+			}
+
+			// Parse the input for x
+			vecX, err := VecFromStr(xInput)
+			if err != nil {
+				fmt.Printf("Error parsing input: %s\n", err)
+				continue
+			}
+			xVecs = append(xVecs, vecX)
+
+			// Encrypt the vector with this client's OTP key
+			c, err := clients[i].Encrypt(vecX, mpk[i], msk.OtpKey[i])
+			if err != nil {
+				fmt.Printf("Failed to encrypt vector: %s\n", err)
+				continue
+			}
+			ciphers[i] = c
+		}
+		fmt.Println("All client inputs encrypted.")
+
+		// Ask for the query vector(s) and derive the FE-key. With
+		// -broadcast-y the same y is asked once and reused for every
+		// client; otherwise each client gets its own y_i, matching what
+		// DamgardMulti actually supports.
+		var yRows []data.Vector
+		if BroadcastY {
+			vecY, err := readVector("Enter the vector <y> as comma-separated integers: ")
+			if err != nil {
+				fmt.Printf("Error reading input, please try again (%s)\n", err)
+				continue
+			}
+			if len(vecY) < 2 {
+				fmt.Println("Vector should have at least 2 elements.")
+				continue
+			}
+			yRows = make([]data.Vector, numClients)
+			for i := range yRows {
+				yRows[i] = vecY
+			}
+		} else {
+			for i := 0; i < numClients; i++ {
+				vecYi, err := readVector(fmt.Sprintf("(%d/%d) Enter the query vector <y> for this client: ", i+1, numClients))
+				if err != nil {
+					fmt.Printf("Error reading input, please try again (%s)\n", err)
+					continue
+				}
+				yRows = append(yRows, vecYi)
+			}
+			if len(yRows) != numClients {
+				continue
+			}
+		}
+
+		matY, err := data.NewMatrix(yRows)
+		if err != nil {
+			fmt.Printf("Error creating matrix: %s\n", err)
+			continue
+		}
+
+		feKey, err := damgard.DeriveKey(msk, matY)
+		if err != nil {
+			fmt.Printf("Failed to derive functional encryption key: %s\n", err)
+			continue
+		}
+
+		// Decrypt the encrypted vectors with the functional key
+		fDec, err := damgard.Decrypt(ciphers, feKey, matY)
+		if err != nil {
+			fmt.Printf("Failed to decrypt: %s\n", err)
+			continue
+		}
+
+		// Calculate the reference inner product Σ_i <x_i, y_i> to verify
+		// the decryption.
+		innerProd := sumInnerProducts(xVecs, yRows)
+
+		if innerProd.Cmp(fDec) == 0 {
+			fmt.Printf("Inner product check 🆗: %s\n", innerProd)
+		} else {
+			fmt.Printf("Inner product check failed: %d != %d\n", innerProd, fDec)
+		}
+		break
+	}
+}
+
+// LWEBoundX and LWEBoundY bound the plaintext and query vector coordinates
+// for the post-quantum LWE scheme. They are kept small relative to the DDH
+// schemes' Bound since LWE's noise growth limits how large <x,y> can get
+// before decryption fails. LWEN is the lattice dimension n used to size the
+// underlying LWE instance.
+const LWEBoundX = 1 << 10
+const LWEBoundY = 1 << 10
+const LWEN = 128
+
+func cliLWE() {
+	for {
+		vecX, err := readVector("Enter the first vector <x> as comma-separated integers (e.g. <5,128,1,48,3>): ")
+		if err != nil {
+			fmt.Printf("Error reading input, please try again (%s)\n", err)
+			continue
+		}
+		if len(vecX) < 2 {
+			fmt.Println("Vector should have at least 2 elements.")
+			continue
+		}
+
+		vecY, err := readVector("Enter the second vector <y>: ")
+		if err != nil {
+			fmt.Printf("Error reading input, please try again (%s)\n", err)
+			continue
+		}
+		if len(vecX) != len(vecY) {
+			fmt.Printf("Vectors should be of the same length, please try again.\n")
+			continue
+		}
+
+		lwe, err := fullysec.NewLWE(len(vecX), LWEN, new(big.Int).SetUint64(LWEBoundX), new(big.Int).SetUint64(LWEBoundY))
+		if err != nil {
+			fmt.Printf("Failed to instantiate LWE: %s\n", err)
+			continue
+		}
+
+		msk, mpk, err := lwe.GenerateMasterKeys()
+		if err != nil {
+			fmt.Printf("Failed to generate keys: %s\n", err)
+			continue
+		}
+
+		fmt.Printf("-----------------\n")
+		p := lwe.Params
+		PrintParams("LWE", field("L", p.L), field("N", p.N), field("M", p.M), field("Q", p.Q), field("BoundX", LWEBoundX), field("BoundY", LWEBoundY))
+		fmt.Printf("-----------------\n")
+
+		// Encrypt the first vector
+		c, err := lwe.Encrypt(vecX, mpk)
+		if err != nil {
+			fmt.Printf("Failed to encrypt vector: %s\n", err)
+			continue
+		}
+		fmt.Printf("Encrypted vector: [%s ]\n", c)
+
+		// Derive the functional encryption key
+		feKey, err := lwe.DeriveKey(msk, vecY)
+		if err != nil {
+			fmt.Printf("Failed to derive functional encryption key: %s\n", err)
+			continue
+		}
+		fmt.Printf("Functional encryption key: %s\n", feKey)
+
+		// Decrypt the encrypted vector with the functional key
+		fDec, err := lwe.Decrypt(c, feKey, vecY)
+		if err != nil {
+			fmt.Printf("Failed to decrypt: %s\n", err)
+			continue
+		}
+		fmt.Printf("Decrypted inner product: %s\n", fDec)
+
+		// Calculate the inner product to verify the decryption
+		innerProd := big.NewInt(0)
+		for i := range vecX {
+			innerProd.Add(innerProd, new(big.Int).Mul(vecX[i], vecY[i]))
+		}
+
+		if innerProd.Cmp(fDec) == 0 {
+			fmt.Printf("Inner product check 🆗: %s\n", innerProd)
+		} else {
+			fmt.Printf("Inner product check failed: %d != %d\n", innerProd, fDec)
+		}
+		break
+	}
+}
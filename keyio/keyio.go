@@ -0,0 +1,365 @@
+// Package keyio encodes and decodes the artifacts produced by the DDH
+// scheme (parameters, keys, ciphertexts, and functional decryption keys) so
+// they can be shipped between processes as files or piped over stdin/stdout.
+//
+// Every artifact is wrapped in a versioned envelope that records the kind of
+// artifact and a fingerprint of the DDHParams it was produced under. Decoding
+// an artifact against a fingerprint it doesn't match (for example, a
+// ciphertext encrypted under one set of params combined with a key derived
+// under another) fails loudly instead of producing a bogus result.
+package keyio
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+)
+
+// Version is the envelope format version. It is bumped whenever the envelope
+// layout (not the scheme itself) changes incompatibly.
+const Version = 1
+
+// Kind identifies the concrete artifact an envelope carries.
+type Kind string
+
+const (
+	KindDDHParams    Kind = "ddh-params"
+	KindDDHSecKey    Kind = "ddh-sec-key"
+	KindDDHPubKey    Kind = "ddh-pub-key"
+	KindCipherVector Kind = "cipher-vector"
+	KindFEKey        Kind = "fe-key"
+)
+
+// envelope is the common wrapper shared by the JSON and binary encodings.
+type envelope struct {
+	Version     int             `json:"version"`
+	Kind        Kind            `json:"kind"`
+	Fingerprint string          `json:"fingerprint"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// ErrFingerprintMismatch is returned when an artifact is decoded against a
+// fingerprint it was not produced under.
+type ErrFingerprintMismatch struct {
+	Kind Kind
+	Want string
+	Got  string
+}
+
+func (e *ErrFingerprintMismatch) Error() string {
+	return fmt.Sprintf("keyio: %s fingerprint mismatch: want %s, got %s", e.Kind, e.Want, e.Got)
+}
+
+// ParamsFingerprint summarizes a DDHParams instance so artifacts produced
+// under different parameters can never be silently mixed.
+func ParamsFingerprint(p *simple.DDHParams) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d|%s|%s|%s|%s", p.L, p.G, p.P, p.Q, p.Bound)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// EncodeParamsJSON encodes a DDHParams instance as a versioned JSON envelope.
+func EncodeParamsJSON(p *simple.DDHParams) ([]byte, error) {
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("keyio: marshal params: %w", err)
+	}
+	return marshalEnvelope(KindDDHParams, ParamsFingerprint(p), payload)
+}
+
+// DecodeParamsJSON decodes a DDHParams instance previously written by
+// EncodeParamsJSON.
+func DecodeParamsJSON(raw []byte) (*simple.DDHParams, error) {
+	env, err := unmarshalEnvelope(raw, KindDDHParams)
+	if err != nil {
+		return nil, err
+	}
+	p := new(simple.DDHParams)
+	if err := json.Unmarshal(env.Payload, p); err != nil {
+		return nil, fmt.Errorf("keyio: unmarshal params: %w", err)
+	}
+	if fp := ParamsFingerprint(p); fp != env.Fingerprint {
+		return nil, &ErrFingerprintMismatch{Kind: KindDDHParams, Want: fp, Got: env.Fingerprint}
+	}
+	return p, nil
+}
+
+// EncodeVectorJSON encodes a data.Vector artifact (a secret/public key, a
+// ciphertext, or similar) as a versioned JSON envelope bound to fingerprint.
+func EncodeVectorJSON(kind Kind, fingerprint string, v data.Vector) ([]byte, error) {
+	payload, err := json.Marshal(bigIntStrings(v))
+	if err != nil {
+		return nil, fmt.Errorf("keyio: marshal %s: %w", kind, err)
+	}
+	return marshalEnvelope(kind, fingerprint, payload)
+}
+
+// DecodeVectorJSON decodes a data.Vector artifact, rejecting it if it was not
+// produced under wantFingerprint.
+func DecodeVectorJSON(raw []byte, kind Kind, wantFingerprint string) (data.Vector, error) {
+	env, err := unmarshalEnvelope(raw, kind)
+	if err != nil {
+		return nil, err
+	}
+	if env.Fingerprint != wantFingerprint {
+		return nil, &ErrFingerprintMismatch{Kind: kind, Want: wantFingerprint, Got: env.Fingerprint}
+	}
+	var strs []string
+	if err := json.Unmarshal(env.Payload, &strs); err != nil {
+		return nil, fmt.Errorf("keyio: unmarshal %s: %w", kind, err)
+	}
+	return vectorFromStrings(strs)
+}
+
+// EncodeScalarJSON encodes a single *big.Int artifact (a functional
+// decryption key) as a versioned JSON envelope bound to fingerprint.
+func EncodeScalarJSON(kind Kind, fingerprint string, x *big.Int) ([]byte, error) {
+	payload, err := json.Marshal(x.String())
+	if err != nil {
+		return nil, fmt.Errorf("keyio: marshal %s: %w", kind, err)
+	}
+	return marshalEnvelope(kind, fingerprint, payload)
+}
+
+// DecodeScalarJSON decodes a single *big.Int artifact, rejecting it if it was
+// not produced under wantFingerprint.
+func DecodeScalarJSON(raw []byte, kind Kind, wantFingerprint string) (*big.Int, error) {
+	env, err := unmarshalEnvelope(raw, kind)
+	if err != nil {
+		return nil, err
+	}
+	if env.Fingerprint != wantFingerprint {
+		return nil, &ErrFingerprintMismatch{Kind: kind, Want: wantFingerprint, Got: env.Fingerprint}
+	}
+	var s string
+	if err := json.Unmarshal(env.Payload, &s); err != nil {
+		return nil, fmt.Errorf("keyio: unmarshal %s: %w", kind, err)
+	}
+	x, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("keyio: invalid %s payload %q", kind, s)
+	}
+	return x, nil
+}
+
+func marshalEnvelope(kind Kind, fingerprint string, payload json.RawMessage) ([]byte, error) {
+	return json.Marshal(envelope{
+		Version:     Version,
+		Kind:        kind,
+		Fingerprint: fingerprint,
+		Payload:     payload,
+	})
+}
+
+func unmarshalEnvelope(raw []byte, want Kind) (*envelope, error) {
+	var env envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return nil, fmt.Errorf("keyio: unmarshal envelope: %w", err)
+	}
+	if env.Version != Version {
+		return nil, fmt.Errorf("keyio: unsupported envelope version %d", env.Version)
+	}
+	if env.Kind != want {
+		return nil, fmt.Errorf("keyio: expected %s artifact, got %s", want, env.Kind)
+	}
+	return &env, nil
+}
+
+func bigIntStrings(v data.Vector) []string {
+	strs := make([]string, len(v))
+	for i, x := range v {
+		strs[i] = x.String()
+	}
+	return strs
+}
+
+func vectorFromStrings(strs []string) (data.Vector, error) {
+	xs := make([]*big.Int, len(strs))
+	for i, s := range strs {
+		x, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return nil, fmt.Errorf("keyio: invalid integer %q", s)
+		}
+		xs[i] = x
+	}
+	return data.NewVector(xs), nil
+}
+
+// Compact binary encoding: a length-prefixed alternative to the JSON
+// envelope for artifacts that are shipped often and benefit from a smaller
+// wire size (ciphertexts, OTP keys). Layout:
+//
+//	[1 byte version][1 byte kind length][kind][32 byte fingerprint]
+//	[4 byte big-endian element count][per element: 1 byte sign][4 byte length][bytes]
+//
+// The fingerprint is stored as its raw 32-byte SHA-256 digest rather than
+// its hex form. Each element's sign byte is 1 if the value is negative and
+// 0 otherwise; the length and bytes that follow are its absolute value.
+// Scalar artifacts omit the element count and carry exactly one element.
+
+// EncodeVectorBinary encodes a data.Vector artifact using the compact binary
+// form.
+func EncodeVectorBinary(kind Kind, fingerprint string, v data.Vector) ([]byte, error) {
+	fpBytes, err := hex.DecodeString(fingerprint)
+	if err != nil || len(fpBytes) != sha256.Size {
+		return nil, fmt.Errorf("keyio: invalid fingerprint %q", fingerprint)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(byte(Version))
+	buf.WriteByte(byte(len(kind)))
+	buf.WriteString(string(kind))
+	buf.Write(fpBytes)
+
+	if err := binary.Write(&buf, binary.BigEndian, uint32(len(v))); err != nil {
+		return nil, err
+	}
+	for _, x := range v {
+		if err := writeBigInt(&buf, x); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeVectorBinary decodes a data.Vector artifact previously written by
+// EncodeVectorBinary, rejecting it if it was not produced under
+// wantFingerprint.
+func DecodeVectorBinary(raw []byte, kind Kind, wantFingerprint string) (data.Vector, error) {
+	r := bytes.NewReader(raw)
+
+	if err := readEnvelopeHeaderBinary(r, kind, wantFingerprint); err != nil {
+		return nil, err
+	}
+
+	var count uint32
+	if err := binary.Read(r, binary.BigEndian, &count); err != nil {
+		return nil, fmt.Errorf("keyio: read element count: %w", err)
+	}
+	xs := make([]*big.Int, count)
+	for i := range xs {
+		x, err := readBigInt(r)
+		if err != nil {
+			return nil, fmt.Errorf("keyio: read element: %w", err)
+		}
+		xs[i] = x
+	}
+	return data.NewVector(xs), nil
+}
+
+// EncodeScalarBinary encodes a single *big.Int artifact (a functional
+// decryption key) using the compact binary form.
+func EncodeScalarBinary(kind Kind, fingerprint string, x *big.Int) ([]byte, error) {
+	fpBytes, err := hex.DecodeString(fingerprint)
+	if err != nil || len(fpBytes) != sha256.Size {
+		return nil, fmt.Errorf("keyio: invalid fingerprint %q", fingerprint)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(byte(Version))
+	buf.WriteByte(byte(len(kind)))
+	buf.WriteString(string(kind))
+	buf.Write(fpBytes)
+
+	if err := writeBigInt(&buf, x); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeScalarBinary decodes a single *big.Int artifact previously written
+// by EncodeScalarBinary, rejecting it if it was not produced under
+// wantFingerprint.
+func DecodeScalarBinary(raw []byte, kind Kind, wantFingerprint string) (*big.Int, error) {
+	r := bytes.NewReader(raw)
+
+	if err := readEnvelopeHeaderBinary(r, kind, wantFingerprint); err != nil {
+		return nil, err
+	}
+
+	x, err := readBigInt(r)
+	if err != nil {
+		return nil, fmt.Errorf("keyio: read scalar: %w", err)
+	}
+	return x, nil
+}
+
+// readEnvelopeHeaderBinary reads and validates the version, kind, and
+// fingerprint shared by every binary-encoded artifact, leaving r positioned
+// at the start of the payload.
+func readEnvelopeHeaderBinary(r *bytes.Reader, kind Kind, wantFingerprint string) error {
+	version, err := r.ReadByte()
+	if err != nil {
+		return fmt.Errorf("keyio: read version: %w", err)
+	}
+	if int(version) != Version {
+		return fmt.Errorf("keyio: unsupported envelope version %d", version)
+	}
+
+	kindLen, err := r.ReadByte()
+	if err != nil {
+		return fmt.Errorf("keyio: read kind length: %w", err)
+	}
+	kindBuf := make([]byte, kindLen)
+	if _, err := io.ReadFull(r, kindBuf); err != nil {
+		return fmt.Errorf("keyio: read kind: %w", err)
+	}
+	if Kind(kindBuf) != kind {
+		return fmt.Errorf("keyio: expected %s artifact, got %s", kind, kindBuf)
+	}
+
+	fpBytes := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(r, fpBytes); err != nil {
+		return fmt.Errorf("keyio: read fingerprint: %w", err)
+	}
+	fp := hex.EncodeToString(fpBytes)
+	if fp != wantFingerprint {
+		return &ErrFingerprintMismatch{Kind: kind, Want: wantFingerprint, Got: fp}
+	}
+	return nil
+}
+
+// writeBigInt writes x as a sign byte followed by a length-prefixed
+// big-endian magnitude, so negative values round-trip correctly.
+func writeBigInt(buf *bytes.Buffer, x *big.Int) error {
+	var sign byte
+	if x.Sign() < 0 {
+		sign = 1
+	}
+	buf.WriteByte(sign)
+	b := x.Bytes()
+	if err := binary.Write(buf, binary.BigEndian, uint32(len(b))); err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
+
+// readBigInt reads a value previously written by writeBigInt.
+func readBigInt(r *bytes.Reader) (*big.Int, error) {
+	sign, err := r.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("read sign: %w", err)
+	}
+	var l uint32
+	if err := binary.Read(r, binary.BigEndian, &l); err != nil {
+		return nil, fmt.Errorf("read length: %w", err)
+	}
+	b := make([]byte, l)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, fmt.Errorf("read bytes: %w", err)
+	}
+	x := new(big.Int).SetBytes(b)
+	if sign == 1 {
+		x.Neg(x)
+	}
+	return x, nil
+}
@@ -0,0 +1,154 @@
+package keyio
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+	"github.com/fentec-project/gofe/innerprod/simple"
+)
+
+func testParams(t *testing.T) *simple.DDHParams {
+	t.Helper()
+	ddh, err := simple.NewDDH(3, 64, big.NewInt(1<<16))
+	if err != nil {
+		t.Fatalf("NewDDH: %s", err)
+	}
+	return ddh.Params
+}
+
+func TestParamsRoundTrip(t *testing.T) {
+	p := testParams(t)
+
+	raw, err := EncodeParamsJSON(p)
+	if err != nil {
+		t.Fatalf("EncodeParamsJSON: %s", err)
+	}
+
+	got, err := DecodeParamsJSON(raw)
+	if err != nil {
+		t.Fatalf("DecodeParamsJSON: %s", err)
+	}
+
+	if got.L != p.L || got.G.Cmp(p.G) != 0 || got.P.Cmp(p.P) != 0 || got.Q.Cmp(p.Q) != 0 || got.Bound.Cmp(p.Bound) != 0 {
+		t.Fatalf("round-tripped params differ: got %+v, want %+v", got, p)
+	}
+}
+
+func TestVectorJSONRoundTrip(t *testing.T) {
+	p := testParams(t)
+	fp := ParamsFingerprint(p)
+	v := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(-2), big.NewInt(1 << 40)})
+
+	raw, err := EncodeVectorJSON(KindCipherVector, fp, v)
+	if err != nil {
+		t.Fatalf("EncodeVectorJSON: %s", err)
+	}
+
+	got, err := DecodeVectorJSON(raw, KindCipherVector, fp)
+	if err != nil {
+		t.Fatalf("DecodeVectorJSON: %s", err)
+	}
+	assertVectorEqual(t, got, v)
+}
+
+func TestVectorBinaryRoundTrip(t *testing.T) {
+	p := testParams(t)
+	fp := ParamsFingerprint(p)
+	v := data.NewVector([]*big.Int{big.NewInt(0), big.NewInt(-5), big.NewInt(1 << 40), big.NewInt(-(1 << 40))})
+
+	raw, err := EncodeVectorBinary(KindDDHSecKey, fp, v)
+	if err != nil {
+		t.Fatalf("EncodeVectorBinary: %s", err)
+	}
+
+	got, err := DecodeVectorBinary(raw, KindDDHSecKey, fp)
+	if err != nil {
+		t.Fatalf("DecodeVectorBinary: %s", err)
+	}
+	assertVectorEqual(t, got, v)
+}
+
+func TestScalarJSONRoundTrip(t *testing.T) {
+	p := testParams(t)
+	fp := ParamsFingerprint(p)
+	x := big.NewInt(424242)
+
+	raw, err := EncodeScalarJSON(KindFEKey, fp, x)
+	if err != nil {
+		t.Fatalf("EncodeScalarJSON: %s", err)
+	}
+
+	got, err := DecodeScalarJSON(raw, KindFEKey, fp)
+	if err != nil {
+		t.Fatalf("DecodeScalarJSON: %s", err)
+	}
+	if got.Cmp(x) != 0 {
+		t.Fatalf("got %s, want %s", got, x)
+	}
+}
+
+func TestScalarBinaryRoundTrip(t *testing.T) {
+	p := testParams(t)
+	fp := ParamsFingerprint(p)
+	x := big.NewInt(-424242)
+
+	raw, err := EncodeScalarBinary(KindFEKey, fp, x)
+	if err != nil {
+		t.Fatalf("EncodeScalarBinary: %s", err)
+	}
+
+	got, err := DecodeScalarBinary(raw, KindFEKey, fp)
+	if err != nil {
+		t.Fatalf("DecodeScalarBinary: %s", err)
+	}
+	if got.Cmp(x) != 0 {
+		t.Fatalf("got %s, want %s", got, x)
+	}
+}
+
+func TestDecodeRejectsFingerprintMismatch(t *testing.T) {
+	p1 := testParams(t)
+	p2 := testParams(t)
+	v := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(2)})
+
+	raw, err := EncodeVectorJSON(KindCipherVector, ParamsFingerprint(p1), v)
+	if err != nil {
+		t.Fatalf("EncodeVectorJSON: %s", err)
+	}
+
+	_, err = DecodeVectorJSON(raw, KindCipherVector, ParamsFingerprint(p2))
+	if err == nil {
+		t.Fatal("expected fingerprint mismatch error, got nil")
+	}
+	if _, ok := err.(*ErrFingerprintMismatch); !ok {
+		t.Fatalf("expected *ErrFingerprintMismatch, got %T: %s", err, err)
+	}
+}
+
+func TestDecodeRejectsWrongKind(t *testing.T) {
+	p := testParams(t)
+	fp := ParamsFingerprint(p)
+	v := data.NewVector([]*big.Int{big.NewInt(1)})
+
+	raw, err := EncodeVectorJSON(KindDDHPubKey, fp, v)
+	if err != nil {
+		t.Fatalf("EncodeVectorJSON: %s", err)
+	}
+
+	if _, err := DecodeVectorJSON(raw, KindDDHSecKey, fp); err == nil {
+		t.Fatal("expected kind mismatch error, got nil")
+	}
+}
+
+func assertVectorEqual(t *testing.T, got, want data.Vector) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("length mismatch: got %d, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Cmp(want[i]) != 0 {
+			t.Fatalf("element %d: got %s, want %s", i, got[i], want[i])
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"math/big"
 	"strconv"
@@ -14,14 +15,28 @@ import (
 const ModLen = 64     // Length of the modulus (bits)
 const Bound = 1 << 16 // Input vector bound
 
-func PrintParams(p *simple.DDHParams) {
-	fmt.Printf("DDH:\n"+
-		"\tL: %d\n"+
-		"\tG: %d\n"+
-		"\tP: %d\n"+
-		"\tQ: %d\n"+
-		"\tBound: %d\n",
-		p.L, p.G, p.P, p.Q, p.Bound)
+// BroadcastY reproduces the old MultiDDH behavior of sending the same query
+// vector y to every client, instead of one y_i per client.
+var BroadcastY bool
+
+// paramField is a single labeled value in a scheme's parameter report.
+type paramField struct {
+	label string
+	value interface{}
+}
+
+// field builds a paramField for use with PrintParams.
+func field(label string, value interface{}) paramField {
+	return paramField{label: label, value: value}
+}
+
+// PrintParams prints a scheme's parameters in the tabular format used
+// throughout the demo, regardless of which scheme they belong to.
+func PrintParams(name string, fields ...paramField) {
+	fmt.Printf("%s:\n", name)
+	for _, f := range fields {
+		fmt.Printf("\t%s: %v\n", f.label, f.value)
+	}
 }
 
 // IntsToVec converts a slice of ints to a data.Vector
@@ -47,10 +62,49 @@ func VecFromStr(s string) (data.Vector, error) {
 	return data.NewVector(x), nil
 }
 
+// readVector prints prompt, reads a single line of input, and parses it as
+// a comma-separated integer vector.
+func readVector(prompt string) (data.Vector, error) {
+	input, err := readLine(prompt)
+	if err != nil {
+		return nil, err
+	}
+	return VecFromStr(input)
+}
+
+// readLine prints prompt and reads back a single whitespace-delimited token
+// of input.
+func readLine(prompt string) (string, error) {
+	fmt.Print(prompt)
+	var input string
+	n, err := fmt.Scanln(&input)
+	if err != nil || n == 0 {
+		return "", fmt.Errorf("error reading input: %w", err)
+	}
+	return input, nil
+}
+
 func main() {
+	flag.BoolVar(&BroadcastY, "broadcast-y", false, "send the same query vector y to every client in multi-client schemes, instead of one y_i per client")
+	flag.Parse()
+
+	// Running as one of the file-based subcommands (setup, encrypt,
+	// derive-key, decrypt) bypasses the interactive menu entirely.
+	if args := flag.Args(); len(args) > 0 && runSubcommand(args[0], args[1:]) {
+		return
+	}
+
 	// Get user input
 	for {
-		fmt.Printf("Schemes:\n" + "\t (1) DDH (s-IND-CPA)\n" + "\t (2) MultiDDH (s-IND-CPA)\n" + "Enter the number of the scheme you want to use: ")
+		fmt.Printf("Schemes:\n" +
+			"\t (1) DDH (selective, DDH assumption)\n" +
+			"\t (2) MultiDDH (selective, DDH assumption)\n" +
+			"\t (3) Damgard (adaptive, DDH assumption)\n" +
+			"\t (4) DamgardMulti (adaptive, DDH assumption)\n" +
+			"\t (5) LWE (adaptive, LWE assumption, post-quantum)\n" +
+			"\t (6) DMCFE (decentralized multi-client, DDH assumption)\n" +
+			"\t (7) VerifiableDDH (DDH + commitment/proof integrity layer)\n" +
+			"Enter the number of the scheme you want to use: ")
 		var scheme int
 		n, err := fmt.Scanln(&scheme)
 		if err != nil || n == 0 {
@@ -60,11 +114,26 @@ func main() {
 
 		switch scheme {
 		case 1:
-			fmt.Println("Selected scheme: DDH (s-IND-CPA), using default parameters.")
+			fmt.Println("Selected scheme: DDH (selective, DDH assumption), using default parameters.")
 			cliDDH()
 		case 2:
-			fmt.Println("Selected scheme: MultiDDH (s-IND-CPA), using default parameters.")
+			fmt.Println("Selected scheme: MultiDDH (selective, DDH assumption), using default parameters.")
 			cliMultiDDH()
+		case 3:
+			fmt.Println("Selected scheme: Damgard (adaptive, DDH assumption), using default parameters.")
+			cliDamgard()
+		case 4:
+			fmt.Println("Selected scheme: DamgardMulti (adaptive, DDH assumption), using default parameters.")
+			cliDamgardMulti()
+		case 5:
+			fmt.Println("Selected scheme: LWE (adaptive, LWE assumption), using default parameters.")
+			cliLWE()
+		case 6:
+			fmt.Println("Selected scheme: DMCFE (decentralized multi-client, DDH assumption).")
+			cliDMCFE()
+		case 7:
+			fmt.Println("Selected scheme: VerifiableDDH (DDH + commitment/proof integrity layer).")
+			cliVerifiableDDH()
 		default:
 			fmt.Println("Invalid selection, please try again.")
 			continue
@@ -144,7 +213,8 @@ func cliDDH() {
 		}
 
 		fmt.Printf("-----------------\n")
-		PrintParams(ddh.Params)
+		p := ddh.Params
+		PrintParams("DDH", field("L", p.L), field("G", p.G), field("P", p.P), field("Q", p.Q), field("Bound", p.Bound))
 		fmt.Printf("-----------------\n")
 
 		// Encrypt the first vector
@@ -217,7 +287,8 @@ func cliMultiDDH() {
 		}
 
 		fmt.Printf("-----------------\n")
-		PrintParams(ddh.Params)
+		p := ddh.Params
+		PrintParams("MultiDDH", field("L", p.L), field("G", p.G), field("P", p.P), field("Q", p.Q), field("Bound", p.Bound))
 		fmt.Printf("-----------------\n")
 
 		clients := make([]*simple.DDHMultiClient, numClients)
@@ -255,32 +326,40 @@ func cliMultiDDH() {
 		}
 		fmt.Println("All client inputs encrypted.")
 
-		// Ask for y and derive the FE-key
-		fmt.Printf("Enter the vector <y> as comma-separated integers: ")
-		var yInput string
-		n, err = fmt.Scanln(&yInput)
-		if err != nil || n == 0 {
-			fmt.Printf("Error reading input, please try again (%s)\n", err)
-			continue
-		}
-
-		// Parse the input for y
-		vecY, err := VecFromStr(yInput)
-		if err != nil {
-			fmt.Printf("Error parsing input: %s\n", err)
-			continue
-		}
-		if len(vecY) < 2 {
-			fmt.Println("Vector should have at least 2 elements.")
-			continue
+		// Ask for the query vector(s) and derive the FE-key. With
+		// -broadcast-y the same y is asked once and reused for every
+		// client; otherwise each client gets its own y_i, matching what
+		// DDHMulti actually supports.
+		var yRows []data.Vector
+		if BroadcastY {
+			vecY, err := readVector("Enter the vector <y> as comma-separated integers: ")
+			if err != nil {
+				fmt.Printf("Error reading input, please try again (%s)\n", err)
+				continue
+			}
+			if len(vecY) < 2 {
+				fmt.Println("Vector should have at least 2 elements.")
+				continue
+			}
+			yRows = make([]data.Vector, numClients)
+			for i := range yRows {
+				yRows[i] = vecY
+			}
+		} else {
+			for i := 0; i < numClients; i++ {
+				vecYi, err := readVector(fmt.Sprintf("(%d/%d) Enter the query vector <y> for this client: ", i+1, numClients))
+				if err != nil {
+					fmt.Printf("Error reading input, please try again (%s)\n", err)
+					continue
+				}
+				yRows = append(yRows, vecYi)
+			}
+			if len(yRows) != numClients {
+				continue
+			}
 		}
 
-		// Repeat Y across matrix
-		vecs := make([]data.Vector, numClients)
-		for i := range vecs {
-			vecs[i] = vecY
-		}
-		matY, err := data.NewMatrix(vecs)
+		matY, err := data.NewMatrix(yRows)
 		if err != nil {
 			fmt.Printf("Error creating matrix: %s\n", err)
 			continue
@@ -299,15 +378,9 @@ func cliMultiDDH() {
 			continue
 		}
 
-		// Calculate the inner product to verify the decryption
-		// XXX: does not work
-		innerProd := big.NewInt(0)
-		for i := range numClients {
-			fmt.Println(i)
-			for j := range vecY {
-				innerProd.Add(innerProd, new(big.Int).Mul(xVecs[i][j], vecY[j]))
-			}
-		}
+		// Calculate the reference inner product Σ_i <x_i, y_i> to verify
+		// the decryption.
+		innerProd := sumInnerProducts(xVecs, yRows)
 
 		if innerProd.Cmp(fDec) == 0 {
 			fmt.Printf("Inner product check 🆗: %s\n", innerProd)
@@ -317,3 +390,15 @@ func cliMultiDDH() {
 		break
 	}
 }
+
+// sumInnerProducts computes Σ_i <xVecs[i], yVecs[i]>, the reference value a
+// multi-client inner-product decryption is checked against.
+func sumInnerProducts(xVecs, yVecs []data.Vector) *big.Int {
+	sum := big.NewInt(0)
+	for i := range xVecs {
+		for j := range yVecs[i] {
+			sum.Add(sum, new(big.Int).Mul(xVecs[i][j], yVecs[i][j]))
+		}
+	}
+	return sum
+}
@@ -67,6 +67,92 @@ func TestDDHMulti(t *testing.T) {
 	t.Logf("Decrypted inner product: %s", fDec)
 }
 
+// TestMultiDDHDecrypt checks that Decrypt recovers the reference inner
+// product Σ_i <x_i, y_i> for both the broadcast-y (same y for every client)
+// and per-client-y (distinct y_i per client) modes, across several
+// (numClients, vecLen) combinations.
+func TestMultiDDHDecrypt(t *testing.T) {
+	bound := new(big.Int).SetUint64(1 << 16)
+
+	type dims struct {
+		numClients int
+		vecLen     int
+	}
+	cases := []dims{
+		{numClients: 1, vecLen: 2},
+		{numClients: 2, vecLen: 2},
+		{numClients: 3, vecLen: 5},
+		{numClients: 5, vecLen: 10},
+	}
+
+	for _, broadcast := range []bool{false, true} {
+		for _, c := range cases {
+			name := fmt.Sprintf("broadcast=%t/%d_%d", broadcast, c.numClients, c.vecLen)
+			t.Run(name, func(t *testing.T) {
+				ddh, err := simple.NewDDHMulti(c.numClients, c.vecLen, 64, bound)
+				if err != nil {
+					t.Fatalf("NewDDHMulti: %s", err)
+				}
+
+				mpk, msk, err := ddh.GenerateMasterKeys()
+				if err != nil {
+					t.Fatalf("GenerateMasterKeys: %s", err)
+				}
+
+				clients := make([]*simple.DDHMultiClient, c.numClients)
+				for i := range clients {
+					clients[i] = simple.NewDDHMultiClient(ddh.Params)
+				}
+
+				xVecs := make([]data.Vector, c.numClients)
+				ciphers := make([]data.Vector, c.numClients)
+				for i := range clients {
+					xVecs[i] = RandomVec(c.vecLen, bound)
+					cipher, err := clients[i].Encrypt(xVecs[i], mpk[i], msk.OtpKey[i])
+					if err != nil {
+						t.Fatalf("Encrypt: %s", err)
+					}
+					ciphers[i] = cipher
+				}
+
+				var yRows []data.Vector
+				if broadcast {
+					vecY := RandomVec(c.vecLen, bound)
+					yRows = make([]data.Vector, c.numClients)
+					for i := range yRows {
+						yRows[i] = vecY
+					}
+				} else {
+					yRows = make([]data.Vector, c.numClients)
+					for i := range yRows {
+						yRows[i] = RandomVec(c.vecLen, bound)
+					}
+				}
+
+				matY, err := data.NewMatrix(yRows)
+				if err != nil {
+					t.Fatalf("NewMatrix: %s", err)
+				}
+
+				feKey, err := ddh.DeriveKey(msk, matY)
+				if err != nil {
+					t.Fatalf("DeriveKey: %s", err)
+				}
+
+				fDec, err := ddh.Decrypt(ciphers, feKey, matY)
+				if err != nil {
+					t.Fatalf("Decrypt: %s", err)
+				}
+
+				want := sumInnerProducts(xVecs, yRows)
+				if fDec.Cmp(want) != 0 {
+					t.Fatalf("Decrypt() = %s, want %s", fDec, want)
+				}
+			})
+		}
+	}
+}
+
 /*
 	var params []struct {
 		name   string
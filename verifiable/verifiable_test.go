@@ -0,0 +1,109 @@
+package verifiable
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fentec-project/gofe/data"
+)
+
+func testScheme(t *testing.T) (*DDH, data.Vector, data.Vector) {
+	t.Helper()
+	ddh, err := NewDDH(3, 64, big.NewInt(1<<16))
+	if err != nil {
+		t.Fatalf("NewDDH: %s", err)
+	}
+	msk, mpk, err := ddh.GenerateMasterKeys()
+	if err != nil {
+		t.Fatalf("GenerateMasterKeys: %s", err)
+	}
+	return ddh, msk, mpk
+}
+
+func TestVerifiableDDHRoundTrip(t *testing.T) {
+	ddh, msk, mpk := testScheme(t)
+	vecX := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)})
+	vecY := data.NewVector([]*big.Int{big.NewInt(4), big.NewInt(5), big.NewInt(6)})
+
+	cipher, err := ddh.Encrypt(vecX, mpk)
+	if err != nil {
+		t.Fatalf("Encrypt: %s", err)
+	}
+	key, err := ddh.DeriveKey(msk, mpk, vecY)
+	if err != nil {
+		t.Fatalf("DeriveKey: %s", err)
+	}
+
+	got, err := ddh.Decrypt(cipher, key, mpk, vecY)
+	if err != nil {
+		t.Fatalf("Decrypt: %s", err)
+	}
+
+	want := innerProduct(vecX, vecY)
+	if got.Cmp(want) != 0 {
+		t.Fatalf("Decrypt() = %s, want %s", got, want)
+	}
+}
+
+func TestVerifiableDDHRejectsTamperedKey(t *testing.T) {
+	ddh, msk, mpk := testScheme(t)
+	vecX := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)})
+	vecY := data.NewVector([]*big.Int{big.NewInt(4), big.NewInt(5), big.NewInt(6)})
+
+	cipher, err := ddh.Encrypt(vecX, mpk)
+	if err != nil {
+		t.Fatalf("Encrypt: %s", err)
+	}
+	key, err := ddh.DeriveKey(msk, mpk, vecY)
+	if err != nil {
+		t.Fatalf("DeriveKey: %s", err)
+	}
+
+	key.FEKeyY = new(big.Int).Add(key.FEKeyY, big.NewInt(1))
+
+	if _, err := ddh.Decrypt(cipher, key, mpk, vecY); err == nil {
+		t.Fatal("expected Decrypt to reject a tampered functional key, got nil error")
+	}
+}
+
+func TestVerifiableDDHRejectsTamperedCiphertext(t *testing.T) {
+	ddh, msk, mpk := testScheme(t)
+	vecX := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)})
+	vecY := data.NewVector([]*big.Int{big.NewInt(4), big.NewInt(5), big.NewInt(6)})
+
+	cipher, err := ddh.Encrypt(vecX, mpk)
+	if err != nil {
+		t.Fatalf("Encrypt: %s", err)
+	}
+	key, err := ddh.DeriveKey(msk, mpk, vecY)
+	if err != nil {
+		t.Fatalf("DeriveKey: %s", err)
+	}
+
+	cipher.Cipher[0] = new(big.Int).Add(cipher.Cipher[0], big.NewInt(1))
+
+	if _, err := ddh.Decrypt(cipher, key, mpk, vecY); err == nil {
+		t.Fatal("expected Decrypt to reject a tampered ciphertext, got nil error")
+	}
+}
+
+func TestVerifiableDDHRejectsForeignProof(t *testing.T) {
+	ddh, _, mpk := testScheme(t)
+	_, otherMsk, otherMpk := testScheme(t)
+	vecX := data.NewVector([]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)})
+	vecY := data.NewVector([]*big.Int{big.NewInt(4), big.NewInt(5), big.NewInt(6)})
+
+	cipher, err := ddh.Encrypt(vecX, mpk)
+	if err != nil {
+		t.Fatalf("Encrypt: %s", err)
+	}
+
+	foreignKey, err := ddh.DeriveKey(otherMsk, otherMpk, vecY)
+	if err != nil {
+		t.Fatalf("DeriveKey: %s", err)
+	}
+
+	if _, err := ddh.Decrypt(cipher, foreignKey, mpk, vecY); err == nil {
+		t.Fatal("expected Decrypt to reject a key/proof generated against a different master key, got nil error")
+	}
+}